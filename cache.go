@@ -0,0 +1,88 @@
+package epss
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cache lets a Client persist the downloaded EPSS corpus between process
+// restarts so a cold start doesn't have to re-download the full CVE
+// universe every time.
+type Cache interface {
+	// Get returns the previously cached payload for key along with the
+	// score_date it was published under. It returns an error if no entry
+	// exists for key.
+	Get(key string) (data []byte, scoreDate time.Time, err error)
+	// Put stores data (the raw gzip payload) under key, tagged with the
+	// score_date it was published under.
+	Put(key string, data []byte, scoreDate time.Time) error
+}
+
+// WithCache configures the Client to consult cache before downloading the
+// corpus, and to persist freshly downloaded payloads back to it.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// fileCache is a Cache backed by a directory on disk. Each key is stored
+// as two files: "<key>.csv.gz" holding the raw gzip payload, and
+// "<key>.meta" holding the score_date it was published under.
+type fileCache struct {
+	dir string
+}
+
+// FileCache returns a Cache rooted at dir, creating the directory if it
+// doesn't already exist.
+func FileCache(dir string) (Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &fileCache{dir: dir}, nil
+}
+
+func (fc *fileCache) Get(key string) ([]byte, time.Time, error) {
+	data, err := os.ReadFile(fc.dataPath(key))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read cache entry for %q: %w", key, err)
+	}
+
+	meta, err := os.ReadFile(fc.metaPath(key))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read cache metadata for %q: %w", key, err)
+	}
+
+	unixSeconds, err := strconv.ParseInt(strings.TrimSpace(string(meta)), 10, 64)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid cache metadata for %q: %w", key, err)
+	}
+
+	return data, time.Unix(unixSeconds, 0).UTC(), nil
+}
+
+func (fc *fileCache) Put(key string, data []byte, scoreDate time.Time) error {
+	if err := os.WriteFile(fc.dataPath(key), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache entry for %q: %w", key, err)
+	}
+
+	meta := strconv.FormatInt(scoreDate.Unix(), 10)
+	if err := os.WriteFile(fc.metaPath(key), []byte(meta), 0o644); err != nil {
+		return fmt.Errorf("failed to write cache metadata for %q: %w", key, err)
+	}
+
+	return nil
+}
+
+func (fc *fileCache) dataPath(key string) string {
+	return filepath.Join(fc.dir, key+".csv.gz")
+}
+
+func (fc *fileCache) metaPath(key string) string {
+	return filepath.Join(fc.dir, key+".meta")
+}
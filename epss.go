@@ -4,9 +4,11 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -28,14 +30,25 @@ type Metadata struct {
 type Client struct {
 	scores         map[string]*Score
 	updateInterval time.Duration
+	cache          Cache
+	retryAttempts  int
+	retryBackoff   time.Duration
+	offlineSource  func() (io.ReadCloser, error)
 	mu             sync.RWMutex
 
+	snapshots   map[string]*snapshot
+	snapshotsMu sync.RWMutex
+
 	DataUrl     string
 	LastUpdated time.Time
 	HttpClient  *http.Client
 	Metadata    *Metadata
 }
 
+// currentCacheKey is the Cache key under which the "current" EPSS corpus
+// is stored, as opposed to dated snapshots fetched for a specific day.
+const currentCacheKey = "current"
+
 type ClientOption func(*Client)
 
 // WithDataURL sets the data URL for the client.
@@ -60,8 +73,10 @@ func NewClient(options ...ClientOption) *Client {
 		HttpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		scores:   make(map[string]*Score),
-		Metadata: &Metadata{},
+		scores:        make(map[string]*Score),
+		Metadata:      &Metadata{},
+		snapshots:     make(map[string]*snapshot),
+		retryAttempts: 1,
 	}
 
 	for _, option := range options {
@@ -72,30 +87,130 @@ func NewClient(options ...ClientOption) *Client {
 }
 
 func (epssClient *Client) updateScores() error {
-	if epssClient.LastUpdated.Format("2006-01-02") == time.Now().Format("2006-01-02") {
+	return epssClient.updateScoresContext(context.Background())
+}
+
+func (epssClient *Client) updateScoresContext(ctx context.Context) error {
+	epssClient.mu.RLock()
+	fresh := epssClient.LastUpdated.Format("2006-01-02") == time.Now().Format("2006-01-02")
+	epssClient.mu.RUnlock()
+	if fresh {
 		return nil
 	}
 
-	req, err := http.NewRequest("GET", epssClient.DataUrl, nil)
+	if epssClient.offlineSource != nil {
+		return epssClient.loadFromOfflineSource()
+	}
+
+	var cachedData []byte
+	var cachedScoreDate time.Time
+	if epssClient.cache != nil {
+		if data, scoreDate, err := epssClient.cache.Get(currentCacheKey); err == nil {
+			cachedData = data
+			cachedScoreDate = scoreDate
+
+			if time.Since(scoreDate) < epssClient.updateInterval {
+				if err := epssClient.loadGzipPayload(data); err != nil {
+					return fmt.Errorf("failed to load cached scores: %w", err)
+				}
+				return nil
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", epssClient.DataUrl, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
 
-	resp, err := epssClient.HttpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %v", err)
+	ifModifiedSince := cachedScoreDate
+	if ifModifiedSince.IsZero() {
+		epssClient.mu.RLock()
+		ifModifiedSince = epssClient.LastUpdated
+		epssClient.mu.RUnlock()
+	}
+	if !ifModifiedSince.IsZero() {
+		req.Header.Set("If-Modified-Since", ifModifiedSince.UTC().Format(http.TimeFormat))
 	}
 
+	resp, err := epssClient.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		if cachedData != nil {
+			if err := epssClient.loadGzipPayload(cachedData); err != nil {
+				return fmt.Errorf("failed to load cached scores: %w", err)
+			}
+		} else {
+			epssClient.touchLastUpdated()
+		}
+		return nil
+	}
+
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	gz, err := gzip.NewReader(io.NopCloser(bytes.NewBuffer(data)))
+	if err := epssClient.loadGzipPayload(data); err != nil {
+		return err
+	}
+
+	if epssClient.cache != nil {
+		epssClient.mu.RLock()
+		scoreDate := epssClient.Metadata.ScoreDate
+		epssClient.mu.RUnlock()
+
+		// Persisting to cache is best-effort: the scores have already been
+		// loaded successfully, so a transient cache-backend problem (disk
+		// full, read-only mount, flaky network cache) shouldn't fail the
+		// whole refresh for every caller.
+		if err := epssClient.cache.Put(currentCacheKey, data, scoreDate); err != nil {
+			log.Printf("epss: failed to persist scores to cache: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// loadGzipPayload parses a gzip-compressed EPSS CSV payload (metadata line
+// plus cve,epss,percentile records) and, on success, atomically replaces
+// epssClient.scores, epssClient.Metadata, and epssClient.LastUpdated under
+// a single lock.
+func (epssClient *Client) loadGzipPayload(data []byte) error {
+	scores, metadata, err := parseScoresPayload(data)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %v", err)
+		return err
+	}
+
+	epssClient.mu.Lock()
+	epssClient.scores = scores
+	epssClient.Metadata = metadata
+	epssClient.LastUpdated = time.Now()
+	epssClient.mu.Unlock()
+
+	return nil
+}
+
+// touchLastUpdated records that the corpus was just confirmed fresh
+// (e.g. a 304 Not Modified with nothing to reload) without otherwise
+// changing it.
+func (epssClient *Client) touchLastUpdated() {
+	epssClient.mu.Lock()
+	epssClient.LastUpdated = time.Now()
+	epssClient.mu.Unlock()
+}
+
+// parseScoresPayload parses a gzip-compressed EPSS CSV payload (metadata
+// line plus cve,epss,percentile records) into a scores map and its
+// accompanying Metadata, without touching any Client state.
+func parseScoresPayload(data []byte) (map[string]*Score, *Metadata, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create gzip reader: %v", err)
 	}
 	defer gz.Close()
 
@@ -105,38 +220,43 @@ func (epssClient *Client) updateScores() error {
 	// Read and parse metadata line
 	metadataLine, err := bufferedReader.ReadString('\n')
 	if err != nil {
-		return fmt.Errorf("failed to read metadata line: %w", err)
+		return nil, nil, fmt.Errorf("failed to read metadata line: %w", err)
+	}
+
+	metadata, err := parseMetadata(metadataLine)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse metadata: %w", err)
 	}
 
-	if err := epssClient.parseMetadata(metadataLine); err != nil {
-		return fmt.Errorf("failed to parse metadata: %w", err)
+	scores, err := parseScoresCSV(bufferedReader)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	csvReader := csv.NewReader(bufferedReader)
+	return scores, metadata, nil
+}
+
+// parseScoresCSV reads the cve,epss,percentile header and records from r
+// (with "#"-prefixed lines treated as comments) into a scores map.
+func parseScoresCSV(r io.Reader) (map[string]*Score, error) {
+	csvReader := csv.NewReader(r)
 	csvReader.Comment = '#'
 
 	// Read header to validate CSV structure
 	header, err := csvReader.Read()
 	if err != nil {
-		return fmt.Errorf("failed to read CSV header: %w", err)
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
 	}
 	if len(header) != 3 || header[0] != "cve" || header[1] != "epss" || header[2] != "percentile" {
-		return fmt.Errorf("invalid CSV header format: expected [cve,epss,percentile], got %v", header)
+		return nil, fmt.Errorf("invalid CSV header format: expected [cve,epss,percentile], got %v", header)
 	}
 
-	// Count remaining lines for capacity allocation
-	data, err = io.ReadAll(bufferedReader)
-	if err != nil {
-		return fmt.Errorf("failed to read CSV data: %w", err)
-	}
-	lineCount := bytes.Count(data, []byte{'\n'})
-
-	// Lock the mutex for the entire update operation
-	epssClient.mu.Lock()
-	newScores := make(map[string]*Score, lineCount)
+	// The EPSS corpus covers the whole CVE universe (~250k entries at the
+	// time of writing); size the map up front instead of buffering the
+	// remaining CSV to count lines, so we only make a single streaming
+	// pass over the data.
+	newScores := make(map[string]*Score, 250_000)
 
-	// Create new reader from the remaining data
-	csvReader = csv.NewReader(bytes.NewReader(data))
 	var lineNum int
 	for {
 		lineNum++
@@ -145,14 +265,12 @@ func (epssClient *Client) updateScores() error {
 			break
 		}
 		if err != nil {
-			epssClient.mu.Unlock()
-			return fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
+			return nil, fmt.Errorf("error reading CSV line %d: %w", lineNum, err)
 		}
 
 		// Validate record length
 		if len(record) != 3 {
-			epssClient.mu.Unlock()
-			return fmt.Errorf("invalid number of fields at line %d: expected 3, got %d", lineNum, len(record))
+			return nil, fmt.Errorf("invalid number of fields at line %d: expected 3, got %d", lineNum, len(record))
 		}
 
 		// Validate CVE format (e.g., CVE-YYYY-NNNNN)
@@ -185,15 +303,10 @@ func (epssClient *Client) updateScores() error {
 		}
 	}
 
-	// Atomic update of the scores map
-	epssClient.scores = newScores
-	epssClient.LastUpdated = time.Now()
-	epssClient.mu.Unlock()
-
-	return nil
+	return newScores, nil
 }
 
-func (epssClient *Client) parseMetadata(line string) error {
+func parseMetadata(line string) (*Metadata, error) {
 	// Remove # prefix and trim spaces
 	line = strings.TrimPrefix(line, "#")
 	line = strings.TrimSpace(line)
@@ -201,9 +314,11 @@ func (epssClient *Client) parseMetadata(line string) error {
 	// Split by comma
 	parts := strings.Split(line, ",")
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid metadata format: expected 2 parts, got %d", len(parts))
+		return nil, fmt.Errorf("invalid metadata format: expected 2 parts, got %d", len(parts))
 	}
 
+	metadata := &Metadata{}
+
 	// Parse each key-value pair
 	for _, part := range parts {
 		// Split on first occurrence of ":"
@@ -216,33 +331,42 @@ func (epssClient *Client) parseMetadata(line string) error {
 
 		switch key {
 		case "model_version":
-			epssClient.Metadata.ModelVersion = value
+			metadata.ModelVersion = value
 		case "score_date":
 			scoreDate, err := time.Parse("2006-01-02T15:04:05+0000", value)
 			if err != nil {
-				return fmt.Errorf("invalid score date format: %w", err)
+				return nil, fmt.Errorf("invalid score date format: %w", err)
 			}
-			epssClient.Metadata.ScoreDate = scoreDate
+			metadata.ScoreDate = scoreDate
 		}
 	}
 
-	if epssClient.Metadata.ModelVersion == "" {
-		return fmt.Errorf("model version not found in metadata")
+	if metadata.ModelVersion == "" {
+		return nil, fmt.Errorf("model version not found in metadata")
 	}
 
-	if epssClient.Metadata.ScoreDate.IsZero() {
-		return fmt.Errorf("score date not found in metadata")
+	if metadata.ScoreDate.IsZero() {
+		return nil, fmt.Errorf("score date not found in metadata")
 	}
 
-	return nil
+	return metadata, nil
 }
 
 // GetAllScores returns all the scores.
 func (epssClient *Client) GetAllScores() ([]*Score, error) {
-	if err := epssClient.updateScores(); err != nil {
+	return epssClient.GetAllScoresContext(context.Background())
+}
+
+// GetAllScoresContext returns all the scores, aborting the underlying
+// download if ctx is cancelled before it completes.
+func (epssClient *Client) GetAllScoresContext(ctx context.Context) ([]*Score, error) {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
 		return nil, fmt.Errorf("failed to update scores: %w", err)
 	}
 
+	epssClient.mu.RLock()
+	defer epssClient.mu.RUnlock()
+
 	scores := make([]*Score, 0, len(epssClient.scores))
 	for _, score := range epssClient.scores {
 		scores = append(scores, score)
@@ -253,15 +377,23 @@ func (epssClient *Client) GetAllScores() ([]*Score, error) {
 
 // GetScore returns the score for the given CVE.
 func (epssClient *Client) GetScore(cve string) (*Score, error) {
+	return epssClient.GetScoreContext(context.Background(), cve)
+}
+
+// GetScoreContext returns the score for the given CVE, aborting the
+// underlying download if ctx is cancelled before it completes.
+func (epssClient *Client) GetScoreContext(ctx context.Context, cve string) (*Score, error) {
 	if !strings.HasPrefix(cve, "CVE-") {
 		return nil, fmt.Errorf("invalid CVE format: %s", cve)
 	}
 
-	if err := epssClient.updateScores(); err != nil {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
 		return nil, fmt.Errorf("failed to update scores: %w", err)
 	}
 
+	epssClient.mu.RLock()
 	score, exists := epssClient.scores[cve]
+	epssClient.mu.RUnlock()
 	if !exists {
 		return nil, fmt.Errorf("score not found for CVE: %s", cve)
 	}
@@ -1,13 +1,192 @@
 package epss_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/KaanSK/go-epss"
 )
 
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// gzipCorpus builds a gzip-compressed EPSS CSV payload (metadata line plus
+// header and records) for use as a canned HTTP response body in tests.
+func gzipCorpus(t *testing.T, csv string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte("#model_version:v2023.03.01,score_date:2024-01-02T00:00:00+0000\n" + csv)); err != nil {
+		t.Fatalf("Failed to write gzip corpus: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip writer: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func newStaticClient(t *testing.T, csv string) *epss.Client {
+	t.Helper()
+
+	body := gzipCorpus(t, csv)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	return epss.NewClient(epss.WithHTTPClient(&http.Client{Transport: transport}))
+}
+
+func TestGetScoreAtDerivesSnapshotURL(t *testing.T) {
+	var gotURL string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotURL = req.URL.String()
+		return nil, fmt.Errorf("network disabled in test")
+	})
+
+	client := epss.NewClient(
+		epss.WithDataURL("https://epss.cyentia.com/epss_scores-current.csv.gz"),
+		epss.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+
+	_, _, err := client.GetScoreAt("CVE-2021-44228", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err == nil {
+		t.Fatal("Expected error from disabled transport, got nil")
+	}
+
+	want := "https://epss.cyentia.com/epss_scores-2024-01-02.csv.gz"
+	if gotURL != want {
+		t.Fatalf("Expected snapshot URL %q, got %q", want, gotURL)
+	}
+}
+
+func TestGetScoreAtParsesSnapshot(t *testing.T) {
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := epss.NewClient(epss.WithHTTPClient(&http.Client{Transport: transport}))
+
+	score, metadata, err := client.GetScoreAt("CVE-2021-44228", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to get score at date: %v", err)
+	}
+	if score.EPSS != 0.97 {
+		t.Fatalf("Expected EPSS 0.97, got %f", score.EPSS)
+	}
+	if metadata.ModelVersion != "v2023.03.01" {
+		t.Fatalf("Expected ModelVersion v2023.03.01, got %s", metadata.ModelVersion)
+	}
+}
+
+func TestGetScoreAtMissingCVE(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+
+	_, _, err := client.GetScoreAt("CVE-9999-9999", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err == nil {
+		t.Fatal("Expected error for CVE missing from snapshot, got nil")
+	}
+}
+
+func TestGetAllScoresAtParsesSnapshot(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\nCVE-2020-0001,0.01,0.10\n")
+
+	scores, metadata, err := client.GetAllScoresAt(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to get all scores at date: %v", err)
+	}
+	if len(scores) != 2 {
+		t.Fatalf("Expected 2 scores, got %d", len(scores))
+	}
+	if metadata.ModelVersion != "v2023.03.01" {
+		t.Fatalf("Expected ModelVersion v2023.03.01, got %s", metadata.ModelVersion)
+	}
+}
+
+func TestGetScoreAtReusesCachedSnapshot(t *testing.T) {
+	var requests int
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := epss.NewClient(epss.WithHTTPClient(&http.Client{Transport: transport}))
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	if _, _, err := client.GetScoreAt("CVE-2021-44228", date); err != nil {
+		t.Fatalf("Failed to get score at date: %v", err)
+	}
+	if _, _, err := client.GetScoreAt("CVE-2021-44228", date); err != nil {
+		t.Fatalf("Failed to get score at date on second call: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected the second lookup to reuse the cached snapshot, got %d requests", requests)
+	}
+}
+
+func TestFileCacheRoundTrip(t *testing.T) {
+	cache, err := epss.FileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file cache: %v", err)
+	}
+
+	scoreDate := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	payload := []byte("not really gzip, just bytes")
+
+	if err := cache.Put("current", payload, scoreDate); err != nil {
+		t.Fatalf("Failed to put cache entry: %v", err)
+	}
+
+	data, gotDate, err := cache.Get("current")
+	if err != nil {
+		t.Fatalf("Failed to get cache entry: %v", err)
+	}
+	if string(data) != string(payload) {
+		t.Fatalf("Expected payload %q, got %q", payload, data)
+	}
+	if !gotDate.Equal(scoreDate) {
+		t.Fatalf("Expected score date %v, got %v", scoreDate, gotDate)
+	}
+}
+
+func TestFileCacheMiss(t *testing.T) {
+	cache, err := epss.FileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create file cache: %v", err)
+	}
+
+	if _, _, err := cache.Get("missing"); err == nil {
+		t.Fatal("Expected error for missing cache entry, got nil")
+	}
+}
+
 func TestClientOptions(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -141,3 +320,229 @@ func TestEPSSOperations(t *testing.T) {
 		})
 	}
 }
+
+func TestGetScores(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\nCVE-2020-0001,0.01,0.10\n")
+
+	found, missing, err := client.GetScores([]string{"CVE-2021-44228", "CVE-9999-9999"})
+	if err != nil {
+		t.Fatalf("Failed to get scores: %v", err)
+	}
+	if _, ok := found["CVE-2021-44228"]; !ok {
+		t.Fatal("Expected CVE-2021-44228 to be found")
+	}
+	if len(missing) != 1 || missing[0] != "CVE-9999-9999" {
+		t.Fatalf("Expected CVE-9999-9999 to be missing, got %v", missing)
+	}
+}
+
+func TestScoresAboveAndIterate(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\nCVE-2020-0001,0.01,0.10\n")
+
+	above, err := client.ScoresAbove(0.5)
+	if err != nil {
+		t.Fatalf("Failed to get scores above threshold: %v", err)
+	}
+	if len(above) != 1 || above[0].CVE != "CVE-2021-44228" {
+		t.Fatalf("Expected only CVE-2021-44228 above threshold, got %v", above)
+	}
+
+	abovePercentile, err := client.ScoresAbovePercentile(0.5)
+	if err != nil {
+		t.Fatalf("Failed to get scores above percentile: %v", err)
+	}
+	if len(abovePercentile) != 1 || abovePercentile[0].CVE != "CVE-2021-44228" {
+		t.Fatalf("Expected only CVE-2021-44228 above percentile, got %v", abovePercentile)
+	}
+
+	var visited int
+	if err := client.Iterate(func(score *epss.Score) bool {
+		visited++
+		return true
+	}); err != nil {
+		t.Fatalf("Failed to iterate scores: %v", err)
+	}
+	if visited != 2 {
+		t.Fatalf("Expected Iterate to visit 2 scores, visited %d", visited)
+	}
+}
+
+func TestScoresAboveRefreshesEmptyClient(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+
+	above, err := client.ScoresAbove(0.5)
+	if err != nil {
+		t.Fatalf("Failed to get scores above threshold: %v", err)
+	}
+	if len(above) != 1 || above[0].CVE != "CVE-2021-44228" {
+		t.Fatalf("Expected ScoresAbove to refresh an empty client before filtering, got %v", above)
+	}
+}
+
+func TestUpdateScoresReturnsErrRemoteUnavailable(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewReader([]byte("not found"))),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := epss.NewClient(epss.WithHTTPClient(&http.Client{Transport: transport}))
+
+	_, err := client.GetAllScores()
+	if err == nil {
+		t.Fatal("Expected error for 404 response, got nil")
+	}
+
+	var remoteErr *epss.ErrRemoteUnavailable
+	if !errors.As(err, &remoteErr) {
+		t.Fatalf("Expected *epss.ErrRemoteUnavailable, got %T: %v", err, err)
+	}
+	if remoteErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", remoteErr.StatusCode)
+	}
+}
+
+func TestUpdateScoresRetriesOn5xx(t *testing.T) {
+	var requests int
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests < 3 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(bytes.NewReader([]byte("try again"))),
+				Header:     make(http.Header),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := epss.NewClient(
+		epss.WithHTTPClient(&http.Client{Transport: transport}),
+		epss.WithRetry(5, time.Millisecond),
+	)
+
+	if _, err := client.GetAllScores(); err != nil {
+		t.Fatalf("Expected retries to eventually succeed, got error: %v", err)
+	}
+	if requests != 3 {
+		t.Fatalf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestUpdateScoresDoesNotSetAcceptEncoding(t *testing.T) {
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+	var gotHeader string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotHeader = req.Header.Get("Accept-Encoding")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := epss.NewClient(epss.WithHTTPClient(&http.Client{Transport: transport}))
+	if _, err := client.GetAllScores(); err != nil {
+		t.Fatalf("Failed to get all scores: %v", err)
+	}
+
+	if gotHeader != "" {
+		t.Fatalf("Expected no Accept-Encoding header to let the transport handle transfer compression, got %q", gotHeader)
+	}
+}
+
+func TestGetScoreContextConcurrent(t *testing.T) {
+	client := newStaticClient(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetScore("CVE-2021-44228"); err != nil {
+				t.Errorf("Failed to get score concurrently: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLoadFromReader(t *testing.T) {
+	client := epss.NewClient()
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+
+	if err := client.LoadFromReader(bytes.NewReader(body)); err != nil {
+		t.Fatalf("Failed to load from reader: %v", err)
+	}
+
+	score, err := client.GetScore("CVE-2021-44228")
+	if err != nil {
+		t.Fatalf("Failed to get score after loading from reader: %v", err)
+	}
+	if score.EPSS != 0.97 {
+		t.Fatalf("Expected EPSS 0.97, got %f", score.EPSS)
+	}
+}
+
+func TestLoadFromCSV(t *testing.T) {
+	client := epss.NewClient()
+	csv := "#model_version:v2023.03.01,score_date:2024-01-02T00:00:00+0000\n" +
+		"cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n"
+
+	if err := client.LoadFromCSV(strings.NewReader(csv)); err != nil {
+		t.Fatalf("Failed to load from CSV: %v", err)
+	}
+
+	score, err := client.GetScore("CVE-2021-44228")
+	if err != nil {
+		t.Fatalf("Failed to get score after loading from CSV: %v", err)
+	}
+	if score.EPSS != 0.97 {
+		t.Fatalf("Expected EPSS 0.97, got %f", score.EPSS)
+	}
+	if client.Metadata.ModelVersion != "v2023.03.01" {
+		t.Fatalf("Expected ModelVersion v2023.03.01, got %s", client.Metadata.ModelVersion)
+	}
+}
+
+func TestWithOfflineSourceBypassesHTTP(t *testing.T) {
+	body := gzipCorpus(t, "cve,epss,percentile\nCVE-2021-44228,0.97,0.99\n")
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		t.Fatal("Expected no HTTP requests when an offline source is configured")
+		return nil, nil
+	})
+
+	client := epss.NewClient(
+		epss.WithHTTPClient(&http.Client{Transport: transport}),
+		epss.WithOfflineSource(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}),
+	)
+
+	score, err := client.GetScore("CVE-2021-44228")
+	if err != nil {
+		t.Fatalf("Failed to get score from offline source: %v", err)
+	}
+	if score.EPSS != 0.97 {
+		t.Fatalf("Expected EPSS 0.97, got %f", score.EPSS)
+	}
+}
+
+func TestGetScoreContextCancelled(t *testing.T) {
+	client := epss.NewClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetScoreContext(ctx, "CVE-2021-44228")
+	if err == nil {
+		t.Fatal("Expected error for cancelled context, got nil")
+	}
+}
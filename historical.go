@@ -0,0 +1,122 @@
+package epss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// snapshot holds the parsed scores and metadata for a single dated EPSS
+// publication, as fetched by GetScoreAt / GetAllScoresAt.
+type snapshot struct {
+	scores   map[string]*Score
+	metadata *Metadata
+}
+
+// GetScoreAt returns the score for the given CVE as published in the EPSS
+// snapshot for date.
+func (epssClient *Client) GetScoreAt(cve string, date time.Time) (*Score, *Metadata, error) {
+	return epssClient.GetScoreAtContext(context.Background(), cve, date)
+}
+
+// GetScoreAtContext returns the score for the given CVE as published in
+// the EPSS snapshot for date, aborting the underlying download if ctx is
+// cancelled before it completes.
+func (epssClient *Client) GetScoreAtContext(ctx context.Context, cve string, date time.Time) (*Score, *Metadata, error) {
+	if !strings.HasPrefix(cve, "CVE-") {
+		return nil, nil, fmt.Errorf("invalid CVE format: %s", cve)
+	}
+
+	snap, err := epssClient.snapshotAt(ctx, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	score, exists := snap.scores[cve]
+	if !exists {
+		return nil, nil, fmt.Errorf("score not found for CVE: %s", cve)
+	}
+
+	return score, snap.metadata, nil
+}
+
+// GetAllScoresAt returns every score published in the EPSS snapshot for
+// date, along with that snapshot's Metadata.
+func (epssClient *Client) GetAllScoresAt(date time.Time) ([]*Score, *Metadata, error) {
+	return epssClient.GetAllScoresAtContext(context.Background(), date)
+}
+
+// GetAllScoresAtContext returns every score published in the EPSS
+// snapshot for date, aborting the underlying download if ctx is
+// cancelled before it completes.
+func (epssClient *Client) GetAllScoresAtContext(ctx context.Context, date time.Time) ([]*Score, *Metadata, error) {
+	snap, err := epssClient.snapshotAt(ctx, date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scores := make([]*Score, 0, len(snap.scores))
+	for _, score := range snap.scores {
+		scores = append(scores, score)
+	}
+
+	return scores, snap.metadata, nil
+}
+
+// snapshotAt returns the parsed snapshot for date, downloading and keying
+// it by date so that repeated lookups don't evict the "current" scores
+// (or each other).
+func (epssClient *Client) snapshotAt(ctx context.Context, date time.Time) (*snapshot, error) {
+	key := date.Format("2006-01-02")
+
+	epssClient.snapshotsMu.RLock()
+	snap, cached := epssClient.snapshots[key]
+	epssClient.snapshotsMu.RUnlock()
+	if cached {
+		return snap, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", epssClient.snapshotURL(date), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := epssClient.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	scores, metadata, err := parseScoresPayload(data)
+	if err != nil {
+		return nil, err
+	}
+
+	snap = &snapshot{scores: scores, metadata: metadata}
+
+	epssClient.snapshotsMu.Lock()
+	epssClient.snapshots[key] = snap
+	epssClient.snapshotsMu.Unlock()
+
+	return snap, nil
+}
+
+// snapshotURL derives the archival URL for the EPSS snapshot published on
+// date from the client's configured DataUrl, e.g.
+// ".../epss_scores-current.csv.gz" becomes ".../epss_scores-2024-01-02.csv.gz".
+func (epssClient *Client) snapshotURL(date time.Time) string {
+	base := epssClient.DataUrl
+	if idx := strings.LastIndex(base, "/"); idx != -1 {
+		base = base[:idx+1]
+	}
+
+	return fmt.Sprintf("%sepss_scores-%s.csv.gz", base, date.Format("2006-01-02"))
+}
@@ -0,0 +1,103 @@
+package epss
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// WithOfflineSource configures the Client to load the corpus exclusively
+// from source, bypassing HTTP entirely. Whenever updateScores (or its
+// Context variant) refreshes the corpus, it invokes source instead of
+// issuing a GET to DataUrl; a call made on the same day as the last
+// successful load does not invoke source at all. Security tooling running in air-gapped environments can
+// pre-stage the daily file via their own transport (S3, an artifact
+// registry, sneakernet) and still get the same Score/Metadata API
+// surface.
+func WithOfflineSource(source func() (io.ReadCloser, error)) ClientOption {
+	return func(c *Client) {
+		c.offlineSource = source
+	}
+}
+
+// loadFromOfflineSource fetches the gzip payload from the configured
+// offline source and loads it the same way a network download would,
+// persisting it to the cache if one is configured.
+func (epssClient *Client) loadFromOfflineSource() error {
+	rc, err := epssClient.offlineSource()
+	if err != nil {
+		return fmt.Errorf("failed to open offline source: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read offline source: %w", err)
+	}
+
+	if err := epssClient.loadGzipPayload(data); err != nil {
+		return err
+	}
+
+	if epssClient.cache != nil {
+		epssClient.mu.RLock()
+		scoreDate := epssClient.Metadata.ScoreDate
+		epssClient.mu.RUnlock()
+
+		// Persisting to cache is best-effort: the scores have already been
+		// loaded successfully, so a transient cache-backend problem
+		// shouldn't fail the whole load for every caller.
+		if err := epssClient.cache.Put(currentCacheKey, data, scoreDate); err != nil {
+			log.Printf("epss: failed to persist scores to cache: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// LoadFromReader parses r as a gzip-compressed EPSS CSV payload (metadata
+// line plus cve,epss,percentile records) and, on success, replaces the
+// Client's current scores and Metadata. It's the same parsing updateScores
+// uses internally for epss_scores-current.csv.gz downloads, exposed so
+// callers can supply the bytes from their own transport.
+func (epssClient *Client) LoadFromReader(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read payload: %w", err)
+	}
+
+	return epssClient.loadGzipPayload(data)
+}
+
+// LoadFromCSV parses r as an already-decompressed EPSS CSV, including the
+// leading "#model_version:...,score_date:..." metadata line, and, on
+// success, replaces the Client's current scores, Metadata, and
+// LastUpdated under a single lock.
+func (epssClient *Client) LoadFromCSV(r io.Reader) error {
+	bufferedReader := bufio.NewReader(r)
+
+	metadataLine, err := bufferedReader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read metadata line: %w", err)
+	}
+
+	metadata, err := parseMetadata(metadataLine)
+	if err != nil {
+		return fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	scores, err := parseScoresCSV(bufferedReader)
+	if err != nil {
+		return err
+	}
+
+	epssClient.mu.Lock()
+	epssClient.scores = scores
+	epssClient.Metadata = metadata
+	epssClient.LastUpdated = time.Now()
+	epssClient.mu.Unlock()
+
+	return nil
+}
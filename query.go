@@ -0,0 +1,129 @@
+package epss
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetScores returns the scores for the given CVEs in a single pass,
+// splitting them into found scores (keyed by CVE) and CVEs with no known
+// EPSS score.
+func (epssClient *Client) GetScores(cves []string) (map[string]*Score, []string, error) {
+	return epssClient.GetScoresContext(context.Background(), cves)
+}
+
+// GetScoresContext returns the scores for the given CVEs in a single pass,
+// aborting the underlying download if ctx is cancelled before it
+// completes.
+func (epssClient *Client) GetScoresContext(ctx context.Context, cves []string) (map[string]*Score, []string, error) {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to update scores: %w", err)
+	}
+
+	epssClient.mu.RLock()
+	defer epssClient.mu.RUnlock()
+
+	found := make(map[string]*Score, len(cves))
+	var missing []string
+	for _, cve := range cves {
+		if score, exists := epssClient.scores[cve]; exists {
+			found[cve] = score
+		} else {
+			missing = append(missing, cve)
+		}
+	}
+
+	return found, missing, nil
+}
+
+// ScoresAbove returns every score whose EPSS value is greater than or
+// equal to epssThreshold, refreshing the corpus first like GetAllScores.
+func (epssClient *Client) ScoresAbove(epssThreshold float32) ([]*Score, error) {
+	return epssClient.ScoresAboveContext(context.Background(), epssThreshold)
+}
+
+// ScoresAboveContext returns every score whose EPSS value is greater than
+// or equal to epssThreshold, aborting the underlying download if ctx is
+// cancelled before it completes.
+func (epssClient *Client) ScoresAboveContext(ctx context.Context, epssThreshold float32) ([]*Score, error) {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to update scores: %w", err)
+	}
+
+	epssClient.mu.RLock()
+	defer epssClient.mu.RUnlock()
+
+	scores := make([]*Score, 0)
+	for _, score := range epssClient.scores {
+		if score.EPSS >= epssThreshold {
+			scores = append(scores, score)
+		}
+	}
+
+	return scores, nil
+}
+
+// ScoresAbovePercentile returns every score whose percentile is greater
+// than or equal to p, refreshing the corpus first like GetAllScores.
+func (epssClient *Client) ScoresAbovePercentile(p float32) ([]*Score, error) {
+	return epssClient.ScoresAbovePercentileContext(context.Background(), p)
+}
+
+// ScoresAbovePercentileContext returns every score whose percentile is
+// greater than or equal to p, aborting the underlying download if ctx is
+// cancelled before it completes.
+func (epssClient *Client) ScoresAbovePercentileContext(ctx context.Context, p float32) ([]*Score, error) {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to update scores: %w", err)
+	}
+
+	epssClient.mu.RLock()
+	defer epssClient.mu.RUnlock()
+
+	scores := make([]*Score, 0)
+	for _, score := range epssClient.scores {
+		if score.Percentile >= p {
+			scores = append(scores, score)
+		}
+	}
+
+	return scores, nil
+}
+
+// Iterate calls visit for each score in the corpus, stopping early if
+// visit returns false. Like GetAllScores, it refreshes the corpus first;
+// unlike GetAllScores, it does not return the corpus to the caller as a
+// slice, but hands scores to visit one at a time. Internally it still
+// copies the score pointers into a slice (see IterateContext).
+func (epssClient *Client) Iterate(visit func(*Score) bool) error {
+	return epssClient.IterateContext(context.Background(), visit)
+}
+
+// IterateContext calls visit for each score in the corpus, stopping
+// early if visit returns false, aborting the underlying download if ctx
+// is cancelled before it completes.
+//
+// The corpus is copied into a local slice under the client lock before
+// visit is called, so visit is free to call back into other Client
+// methods (including a nested Iterate) without deadlocking against a
+// concurrent refresh.
+func (epssClient *Client) IterateContext(ctx context.Context, visit func(*Score) bool) error {
+	if err := epssClient.updateScoresContext(ctx); err != nil {
+		return fmt.Errorf("failed to update scores: %w", err)
+	}
+
+	epssClient.mu.RLock()
+	scores := make([]*Score, 0, len(epssClient.scores))
+	for _, score := range epssClient.scores {
+		scores = append(scores, score)
+	}
+	epssClient.mu.RUnlock()
+
+	for _, score := range scores {
+		if !visit(score) {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package epss
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithRetry configures the Client to retry transient failures (network
+// errors, 5xx responses, and 429 responses) up to attempts times, using
+// exponential backoff starting at backoff and jittered by up to +/-50%.
+// 429 responses honor the server's Retry-After header instead of the
+// computed backoff. The default Client makes a single attempt.
+func WithRetry(attempts int, backoff time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryAttempts = attempts
+		c.retryBackoff = backoff
+	}
+}
+
+// ErrRemoteUnavailable indicates the EPSS mirror responded with a status
+// code other than 2xx or 304 Not Modified.
+type ErrRemoteUnavailable struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ErrRemoteUnavailable) Error() string {
+	return fmt.Sprintf("epss: remote unavailable: status %d: %s", e.StatusCode, e.Body)
+}
+
+// doRequest sends req, retrying on network errors and 5xx/429 responses
+// according to the Client's retry policy. It returns the first 2xx or 304
+// response, or the last error encountered once attempts are exhausted.
+func (epssClient *Client) doRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+	attempts := epssClient.retryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	var retryAfter time.Duration
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			wait := retryAfter
+			if wait <= 0 {
+				wait = backoffWithJitter(epssClient.retryBackoff, attempt)
+			}
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+		retryAfter = 0
+
+		resp, err := epssClient.HttpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to send request: %w", err)
+			continue
+		}
+
+		if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+			return resp, nil
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		remoteErr := &ErrRemoteUnavailable{StatusCode: resp.StatusCode, Body: string(body)}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return nil, remoteErr
+		}
+
+		lastErr = remoteErr
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+	}
+
+	return nil, lastErr
+}
+
+// maxBackoff caps the exponential growth in backoffWithJitter so a large
+// attempts count can't shift a time.Duration into overflow.
+const maxBackoff = time.Minute
+
+// backoffWithJitter returns base doubled for each attempt beyond the
+// first (capped at maxBackoff), randomized to within +/-50% of that
+// value.
+func backoffWithJitter(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	exp := base
+	for i := 1; i < attempt && exp < maxBackoff; i++ {
+		exp *= 2
+	}
+	if exp > maxBackoff {
+		exp = maxBackoff
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(exp)+1)) - exp/2
+
+	return exp + jitter
+}
+
+// parseRetryAfter interprets a Retry-After header value, which may be a
+// number of seconds or an HTTP date. It returns 0 if value is empty or
+// unparseable.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when)
+	}
+
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
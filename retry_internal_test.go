@@ -0,0 +1,18 @@
+package epss
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitterBounded(t *testing.T) {
+	for _, attempt := range []int{1, 2, 10, 64, 1000} {
+		d := backoffWithJitter(time.Nanosecond, attempt)
+		if d < 0 {
+			t.Fatalf("attempt %d: expected non-negative backoff, got %v", attempt, d)
+		}
+		if d > maxBackoff+maxBackoff/2 {
+			t.Fatalf("attempt %d: expected backoff within +50%% of maxBackoff, got %v", attempt, d)
+		}
+	}
+}